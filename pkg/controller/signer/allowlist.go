@@ -0,0 +1,98 @@
+package signer
+
+import (
+	"crypto/x509"
+	"fmt"
+
+	csrv1 "k8s.io/api/certificates/v1"
+)
+
+// AllowlistConfig constrains the identities this signer is willing to issue
+// certificates for. It is surfaced on the operator config so cluster admins
+// can tighten what OVN-Kubernetes clients may request. An allowlist set there
+// replaces DefaultAllowlist as a whole; it is not checked to be narrower than
+// the defaults.
+type AllowlistConfig struct {
+	// CommonNames is the set of permitted Subject Common Names.
+	CommonNames []string
+	// Organizations is the set of permitted Subject Organizations.
+	Organizations []string
+	// DNSNames is the set of permitted SAN DNS names. A request with no DNS
+	// SANs is always permitted.
+	DNSNames []string
+	// IPAddresses is the set of permitted SAN IP addresses, as strings. A
+	// request with no IP SANs is always permitted.
+	IPAddresses []string
+	// KeyUsages is the set of permitted requested key usages.
+	KeyUsages []csrv1.KeyUsage
+}
+
+// DefaultAllowlist returns the allowlist matching what OVN-Kubernetes's
+// ovnkube-node and ovnkube-control-plane clients request today.
+func DefaultAllowlist() AllowlistConfig {
+	return AllowlistConfig{
+		CommonNames: []string{
+			"ovnkube-node",
+			"ovnkube-control-plane",
+		},
+		Organizations: []string{"system:ovn-kubernetes"},
+		KeyUsages: []csrv1.KeyUsage{
+			csrv1.UsageDigitalSignature,
+			csrv1.UsageKeyEncipherment,
+			csrv1.UsageClientAuth,
+		},
+	}
+}
+
+// validateCSRContent checks a decoded certificate request against the
+// allowlist, returning a descriptive error for the first field that falls
+// outside of it.
+func validateCSRContent(csr *csrv1.CertificateSigningRequest, certReq *x509.CertificateRequest, allow AllowlistConfig) error {
+	if !stringAllowed(allow.CommonNames, certReq.Subject.CommonName) {
+		return fmt.Errorf("common name %q is not in the permitted allowlist", certReq.Subject.CommonName)
+	}
+
+	for _, org := range certReq.Subject.Organization {
+		if !stringAllowed(allow.Organizations, org) {
+			return fmt.Errorf("organization %q is not in the permitted allowlist", org)
+		}
+	}
+
+	for _, name := range certReq.DNSNames {
+		if !stringAllowed(allow.DNSNames, name) {
+			return fmt.Errorf("DNS SAN %q is not in the permitted allowlist", name)
+		}
+	}
+
+	for _, ip := range certReq.IPAddresses {
+		if !stringAllowed(allow.IPAddresses, ip.String()) {
+			return fmt.Errorf("IP SAN %q is not in the permitted allowlist", ip)
+		}
+	}
+
+	for _, usage := range csr.Spec.Usages {
+		if !usageAllowed(allow.KeyUsages, usage) {
+			return fmt.Errorf("key usage %q is not in the permitted allowlist", usage)
+		}
+	}
+
+	return nil
+}
+
+func stringAllowed(allowed []string, value string) bool {
+	for _, v := range allowed {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func usageAllowed(allowed []csrv1.KeyUsage, value csrv1.KeyUsage) bool {
+	for _, v := range allowed {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package signer
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	csrv1 "k8s.io/api/certificates/v1"
+)
+
+func TestValidateCSRContent(t *testing.T) {
+	allow := AllowlistConfig{
+		CommonNames:   []string{"ovnkube-node"},
+		Organizations: []string{"system:ovn-kubernetes"},
+		DNSNames:      []string{"example.com"},
+		IPAddresses:   []string{"10.0.0.1"},
+		KeyUsages:     []csrv1.KeyUsage{csrv1.UsageClientAuth},
+	}
+
+	tests := []struct {
+		name    string
+		cn      string
+		org     string
+		dns     string
+		ip      string
+		usage   csrv1.KeyUsage
+		wantErr bool
+	}{
+		{name: "allowed", cn: "ovnkube-node", org: "system:ovn-kubernetes", dns: "example.com", ip: "10.0.0.1", usage: csrv1.UsageClientAuth},
+		{name: "disallowed common name", cn: "evil", org: "system:ovn-kubernetes", usage: csrv1.UsageClientAuth, wantErr: true},
+		{name: "disallowed organization", cn: "ovnkube-node", org: "evil-org", usage: csrv1.UsageClientAuth, wantErr: true},
+		{name: "disallowed DNS SAN", cn: "ovnkube-node", org: "system:ovn-kubernetes", dns: "evil.com", usage: csrv1.UsageClientAuth, wantErr: true},
+		{name: "disallowed IP SAN", cn: "ovnkube-node", org: "system:ovn-kubernetes", ip: "10.0.0.2", usage: csrv1.UsageClientAuth, wantErr: true},
+		{name: "disallowed key usage", cn: "ovnkube-node", org: "system:ovn-kubernetes", usage: csrv1.UsageServerAuth, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certReq := &x509.CertificateRequest{
+				Subject: pkix.Name{CommonName: tt.cn, Organization: []string{tt.org}},
+			}
+			if tt.dns != "" {
+				certReq.DNSNames = []string{tt.dns}
+			}
+			if tt.ip != "" {
+				certReq.IPAddresses = []net.IP{net.ParseIP(tt.ip)}
+			}
+
+			csr := &csrv1.CertificateSigningRequest{
+				Spec: csrv1.CertificateSigningRequestSpec{Usages: []csrv1.KeyUsage{tt.usage}},
+			}
+
+			err := validateCSRContent(csr, certReq, allow)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateCSRContent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStringAllowed(t *testing.T) {
+	allowed := []string{"a", "b"}
+	if !stringAllowed(allowed, "a") {
+		t.Errorf("expected %q to be allowed", "a")
+	}
+	if stringAllowed(allowed, "c") {
+		t.Errorf("expected %q to not be allowed", "c")
+	}
+}
+
+func TestUsageAllowed(t *testing.T) {
+	allowed := []csrv1.KeyUsage{csrv1.UsageClientAuth}
+	if !usageAllowed(allowed, csrv1.UsageClientAuth) {
+		t.Errorf("expected %q to be allowed", csrv1.UsageClientAuth)
+	}
+	if usageAllowed(allowed, csrv1.UsageServerAuth) {
+		t.Errorf("expected %q to not be allowed", csrv1.UsageServerAuth)
+	}
+}
@@ -0,0 +1,43 @@
+package signer
+
+import (
+	"context"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	csrv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// isApprovalAuthorized performs a SubjectAccessReview to check whether the
+// CSR's submitter is actually allowed to request approval of certificates
+// from this signer, rather than trusting that create access on the CSR
+// resource implies approve access. This mirrors the check the built-in
+// kube-controller-manager CSR approvers and machine-approver perform.
+func (r *ReconcileCSR) isApprovalAuthorized(ctx context.Context, csr *csrv1.CertificateSigningRequest) (bool, error) {
+	extra := map[string]authorizationv1.ExtraValue{}
+	for k, v := range csr.Spec.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   csr.Spec.Username,
+			Groups: csr.Spec.Groups,
+			UID:    csr.Spec.UID,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Group:    "certificates.k8s.io",
+				Resource: "signers",
+				Verb:     "approve",
+				Name:     signerName,
+			},
+		},
+	}
+
+	resp, err := r.clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	return resp.Status.Allowed, nil
+}
@@ -0,0 +1,194 @@
+package signer
+
+import (
+	"context"
+	"log"
+	"time"
+
+	csrv1 "k8s.io/api/certificates/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// CleanerConfig tunes the garbage collector that removes stale CSRs created
+// for this signer, mirroring upstream's pkg/controller/certificates/cleaner.
+type CleanerConfig struct {
+	// Disabled is a kill-switch: when true, the cleaner never deletes anything.
+	Disabled bool
+	// Period is how often the cleaner scans for stale CSRs.
+	Period time.Duration
+	// DeniedTTL is how long a Denied CSR is kept before being deleted.
+	DeniedTTL time.Duration
+	// FailedTTL is how long a Failed CSR is kept before being deleted.
+	FailedTTL time.Duration
+	// PendingTTL is how long a CSR with no terminal condition is kept
+	// before being deleted.
+	PendingTTL time.Duration
+}
+
+// DefaultCleanerConfig matches upstream's cleaner defaults for Denied and
+// Failed requests, with a more generous TTL for requests that were never
+// acted on at all.
+func DefaultCleanerConfig() CleanerConfig {
+	return CleanerConfig{
+		Period:     time.Hour,
+		DeniedTTL:  time.Hour,
+		FailedTTL:  time.Hour,
+		PendingTTL: 24 * time.Hour,
+	}
+}
+
+// cleanerSweepName is the fixed object name used to trigger a sweep; the
+// cleaner ignores the reconcile.Request's contents and always scans every
+// CSR for this signer.
+const cleanerSweepName = "sweep"
+
+// reconcileCleaner periodically deletes CSRs owned by this signer that have
+// outlived their usefulness: issued certificates whose NotAfter has passed,
+// and Denied/Failed/pending requests older than their configured TTL. It
+// never touches CSRs for other signers.
+type reconcileCleaner struct {
+	client crclient.Client
+	cfg    CleanerConfig
+	events chan event.GenericEvent
+}
+
+func addCleanerController(mgr manager.Manager, cfg CleanerConfig) error {
+	r := &reconcileCleaner{
+		client: mgr.GetClient(),
+		cfg:    cfg,
+		events: make(chan event.GenericEvent, 1),
+	}
+
+	c, err := controller.New("signer-csr-cleaner", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	if err := c.Watch(&source.Channel{Source: r.events}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	return mgr.Add(&cleanerTicker{events: r.events, period: cfg.Period})
+}
+
+func (r *reconcileCleaner) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	if r.cfg.Disabled {
+		return reconcile.Result{}, nil
+	}
+
+	var list csrv1.CertificateSigningRequestList
+	if err := r.client.List(ctx, &list); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	now := time.Now()
+	for i := range list.Items {
+		csr := &list.Items[i]
+		if csr.Spec.SignerName != signerName {
+			continue
+		}
+
+		reason, stale := r.isStale(csr, now)
+		if !stale {
+			continue
+		}
+
+		if err := r.client.Delete(ctx, csr); err != nil && !apierrors.IsNotFound(err) {
+			log.Printf("signer-csr-cleaner: unable to delete CSR %s (%s): %v", csr.Name, reason, err)
+			continue
+		}
+		log.Printf("signer-csr-cleaner: deleted CSR %s (%s)", csr.Name, reason)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// isStale classifies a CSR's lifecycle state and reports whether it has
+// outlived the TTL for that state.
+func (r *reconcileCleaner) isStale(csr *csrv1.CertificateSigningRequest, now time.Time) (string, bool) {
+	approved, denied := getCertApprovalCondition(&csr.Status)
+
+	switch {
+	case approved && len(csr.Status.Certificate) != 0:
+		cert, err := decodeCertificate(csr.Status.Certificate)
+		if err != nil {
+			// Leave anything we can't parse for a human to look at.
+			return "", false
+		}
+		return "issued certificate expired", now.After(cert.NotAfter)
+
+	case denied:
+		t, ok := conditionTime(csr, csrv1.CertificateDenied)
+		return "denied TTL elapsed", ok && now.Sub(t) > r.cfg.DeniedTTL
+
+	case hasFailedCondition(csr):
+		t, ok := conditionTime(csr, csrv1.CertificateFailed)
+		return "failed TTL elapsed", ok && now.Sub(t) > r.cfg.FailedTTL
+
+	default:
+		return "pending TTL elapsed", now.Sub(csr.CreationTimestamp.Time) > r.cfg.PendingTTL
+	}
+}
+
+func hasFailedCondition(csr *csrv1.CertificateSigningRequest) bool {
+	for _, c := range csr.Status.Conditions {
+		if c.Type == csrv1.CertificateFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionTime returns when condition t was last touched, preferring
+// LastTransitionTime and falling back to LastUpdateTime.
+func conditionTime(csr *csrv1.CertificateSigningRequest, t csrv1.RequestConditionType) (time.Time, bool) {
+	for _, c := range csr.Status.Conditions {
+		if c.Type != t {
+			continue
+		}
+		if !c.LastTransitionTime.IsZero() {
+			return c.LastTransitionTime.Time, true
+		}
+		return c.LastUpdateTime.Time, !c.LastUpdateTime.IsZero()
+	}
+	return time.Time{}, false
+}
+
+// cleanerTicker periodically triggers a cleaner sweep, plus one at startup
+// so long-lived clusters don't wait a full period before the first GC pass.
+type cleanerTicker struct {
+	events chan event.GenericEvent
+	period time.Duration
+}
+
+func (t *cleanerTicker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(t.period)
+	defer ticker.Stop()
+
+	t.trigger()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.trigger()
+		}
+	}
+}
+
+func (t *cleanerTicker) trigger() {
+	csr := &csrv1.CertificateSigningRequest{}
+	csr.Name = cleanerSweepName
+	select {
+	case t.events <- event.GenericEvent{Object: csr}:
+	default:
+		// A sweep is already queued; this tick can be skipped.
+	}
+}
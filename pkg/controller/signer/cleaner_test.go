@@ -0,0 +1,109 @@
+package signer
+
+import (
+	"encoding/pem"
+	"testing"
+	"time"
+
+	csrv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileCleanerIsStale(t *testing.T) {
+	r := &reconcileCleaner{cfg: CleanerConfig{
+		DeniedTTL:  time.Hour,
+		FailedTTL:  time.Hour,
+		PendingTTL: 24 * time.Hour,
+	}}
+
+	now := time.Now()
+
+	t.Run("approved with expired certificate is stale", func(t *testing.T) {
+		cert, _ := selfSignedTestCert(t, 1, now.Add(-time.Minute))
+		csr := &csrv1.CertificateSigningRequest{
+			Status: csrv1.CertificateSigningRequestStatus{
+				Conditions:  []csrv1.CertificateSigningRequestCondition{{Type: csrv1.CertificateApproved, Status: "True"}},
+				Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}),
+			},
+		}
+		if _, stale := r.isStale(csr, now); !stale {
+			t.Fatalf("expected an approved CSR with an expired certificate to be stale")
+		}
+	})
+
+	t.Run("approved with unexpired certificate is not stale", func(t *testing.T) {
+		cert, _ := selfSignedTestCert(t, 2, now.Add(time.Hour))
+		csr := &csrv1.CertificateSigningRequest{
+			Status: csrv1.CertificateSigningRequestStatus{
+				Conditions:  []csrv1.CertificateSigningRequestCondition{{Type: csrv1.CertificateApproved, Status: "True"}},
+				Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}),
+			},
+		}
+		if _, stale := r.isStale(csr, now); stale {
+			t.Fatalf("expected an approved CSR with an unexpired certificate to not be stale")
+		}
+	})
+
+	t.Run("denied past its TTL is stale", func(t *testing.T) {
+		csr := &csrv1.CertificateSigningRequest{
+			Status: csrv1.CertificateSigningRequestStatus{
+				Conditions: []csrv1.CertificateSigningRequestCondition{{
+					Type:               csrv1.CertificateDenied,
+					Status:             "True",
+					LastTransitionTime: metav1.NewTime(now.Add(-2 * time.Hour)),
+				}},
+			},
+		}
+		if _, stale := r.isStale(csr, now); !stale {
+			t.Fatalf("expected a CSR denied 2h ago to be stale with a 1h DeniedTTL")
+		}
+	})
+
+	t.Run("denied within its TTL is not stale", func(t *testing.T) {
+		csr := &csrv1.CertificateSigningRequest{
+			Status: csrv1.CertificateSigningRequestStatus{
+				Conditions: []csrv1.CertificateSigningRequestCondition{{
+					Type:               csrv1.CertificateDenied,
+					Status:             "True",
+					LastTransitionTime: metav1.NewTime(now.Add(-10 * time.Minute)),
+				}},
+			},
+		}
+		if _, stale := r.isStale(csr, now); stale {
+			t.Fatalf("expected a CSR denied 10m ago to not be stale with a 1h DeniedTTL")
+		}
+	})
+
+	t.Run("failed past its TTL is stale", func(t *testing.T) {
+		csr := &csrv1.CertificateSigningRequest{
+			Status: csrv1.CertificateSigningRequestStatus{
+				Conditions: []csrv1.CertificateSigningRequestCondition{{
+					Type:               csrv1.CertificateFailed,
+					Status:             "True",
+					LastTransitionTime: metav1.NewTime(now.Add(-2 * time.Hour)),
+				}},
+			},
+		}
+		if _, stale := r.isStale(csr, now); !stale {
+			t.Fatalf("expected a CSR failed 2h ago to be stale with a 1h FailedTTL")
+		}
+	})
+
+	t.Run("stale pending is stale", func(t *testing.T) {
+		csr := &csrv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-25 * time.Hour))},
+		}
+		if _, stale := r.isStale(csr, now); !stale {
+			t.Fatalf("expected a 25h old pending CSR to be stale with a 24h PendingTTL")
+		}
+	})
+
+	t.Run("fresh pending is not stale", func(t *testing.T) {
+		csr := &csrv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+		}
+		if _, stale := r.isStale(csr, now); stale {
+			t.Fatalf("expected a 1m old pending CSR to not be stale")
+		}
+	})
+}
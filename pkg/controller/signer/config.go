@@ -0,0 +1,75 @@
+package signer
+
+// Config aggregates every cluster-admin-facing knob this controller exposes.
+// It is meant to be populated from the corresponding fields on the network
+// operator's config CR and passed in to Add; a field left nil means the
+// admin didn't set it, so this package's default for that knob is used
+// instead. This way a CR that only overrides one knob doesn't silently reset
+// the others to their zero value.
+type Config struct {
+	// Allowlist constrains the Common Names, SANs, organizations and key
+	// usages this signer will approve.
+	Allowlist *AllowlistConfig
+	// Duration bounds the certificate lifetime this signer will honor.
+	Duration *DurationConfig
+	// Backend selects and configures the signing backend (in-process CA vs.
+	// external CA).
+	Backend *BackendConfig
+	// Rotation tunes when the in-process CA is due for rotation and how long
+	// a replaced CA is kept available during the overlap window.
+	Rotation *RotationConfig
+	// ForceRotate mirrors the ForceRotate annotation on the operator's
+	// config CR: when true, the in-process CA is regenerated immediately,
+	// regardless of Rotation.RenewalThreshold.
+	ForceRotate bool
+	// Cleaner tunes the garbage collector that removes stale CSRs, including
+	// its kill-switch.
+	Cleaner *CleanerConfig
+}
+
+// allowlist returns cfg.Allowlist, or DefaultAllowlist() if the admin left it
+// unset.
+func (c Config) allowlist() AllowlistConfig {
+	if c.Allowlist != nil {
+		return *c.Allowlist
+	}
+	return DefaultAllowlist()
+}
+
+// duration returns cfg.Duration, or DefaultDurationConfig() if the admin left
+// it unset.
+func (c Config) duration() DurationConfig {
+	if c.Duration != nil {
+		return *c.Duration
+	}
+	return DefaultDurationConfig()
+}
+
+// backend returns cfg.Backend, or DefaultBackendConfig() if the admin left it
+// unset.
+func (c Config) backend() BackendConfig {
+	if c.Backend != nil {
+		return *c.Backend
+	}
+	return DefaultBackendConfig()
+}
+
+// rotation returns cfg.Rotation, or DefaultRotationConfig() if the admin left
+// it unset, with ForceRotate folded in from the top level.
+func (c Config) rotation() RotationConfig {
+	r := DefaultRotationConfig()
+	if c.Rotation != nil {
+		r = *c.Rotation
+	}
+	r.ForceRotate = c.ForceRotate
+	return r
+}
+
+// cleaner returns cfg.Cleaner, or DefaultCleanerConfig() if the admin left it
+// unset.
+func (c Config) cleaner() CleanerConfig {
+	if c.Cleaner != nil {
+		return *c.Cleaner
+	}
+	return DefaultCleanerConfig()
+}
@@ -0,0 +1,71 @@
+package signer
+
+import (
+	"fmt"
+	"time"
+
+	csrv1 "k8s.io/api/certificates/v1"
+)
+
+// requestDurationAnnotation is the cert-manager-style annotation used by
+// clients that predate the upstream CSR ExpirationSeconds field to request a
+// specific certificate lifetime. The value is a Go duration string, as
+// accepted by time.ParseDuration, e.g. "1h".
+const requestDurationAnnotation = "experimental.cert-manager.io/request-duration"
+
+// DurationConfig bounds the certificate lifetime this signer will honor.
+type DurationConfig struct {
+	// MinDuration is the shortest lifetime a request may ask for.
+	MinDuration time.Duration
+	// MaxDuration is the longest lifetime a request may ask for, subject to
+	// also being clamped to the signing CA's own NotAfter.
+	MaxDuration time.Duration
+}
+
+// DefaultDurationConfig matches the lifetime OVN-Kubernetes's workload
+// certificates have historically used.
+func DefaultDurationConfig() DurationConfig {
+	return DurationConfig{
+		MinDuration: time.Hour,
+		MaxDuration: 24 * time.Hour,
+	}
+}
+
+// requestedDuration returns the lifetime a CSR asked for, preferring the
+// upstream ExpirationSeconds field and falling back to the cert-manager
+// request-duration annotation. The second return value is false if neither
+// was set, in which case the caller should fall back to a default.
+func requestedDuration(csr *csrv1.CertificateSigningRequest) (time.Duration, bool) {
+	if csr.Spec.ExpirationSeconds != nil {
+		return time.Duration(*csr.Spec.ExpirationSeconds) * time.Second, true
+	}
+
+	if raw, ok := csr.Annotations[requestDurationAnnotation]; ok {
+		if d, err := time.ParseDuration(raw); err == nil {
+			return d, true
+		}
+	}
+
+	return 0, false
+}
+
+// clampDuration bounds a requested certificate lifetime to the configured
+// [min, max] range. It returns the duration to sign for and, if the request
+// fell outside that range, a human-readable explanation of the clamp.
+//
+// This is the admin-facing clamp surfaced as a CSR condition; the signer
+// backend additionally enforces its own hard ceiling (e.g. an in-process CA
+// will never sign past its own NotAfter) without reporting it here, since
+// that ceiling isn't meaningful to an external CA backend.
+func clampDuration(requested time.Duration, cfg DurationConfig) (time.Duration, string) {
+	ceiling := cfg.MaxDuration
+
+	switch {
+	case requested > ceiling:
+		return ceiling, fmt.Sprintf("requested duration %s exceeds the maximum of %s; clamped to %s", requested, ceiling, ceiling)
+	case requested < cfg.MinDuration:
+		return cfg.MinDuration, fmt.Sprintf("requested duration %s is below the minimum of %s; clamped to %s", requested, cfg.MinDuration, cfg.MinDuration)
+	default:
+		return requested, ""
+	}
+}
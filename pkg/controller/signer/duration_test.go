@@ -0,0 +1,65 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	csrv1 "k8s.io/api/certificates/v1"
+)
+
+func TestRequestedDuration(t *testing.T) {
+	seconds := int32(3600)
+	csrWithExpiration := &csrv1.CertificateSigningRequest{
+		Spec: csrv1.CertificateSigningRequestSpec{ExpirationSeconds: &seconds},
+	}
+	if d, ok := requestedDuration(csrWithExpiration); !ok || d != time.Hour {
+		t.Fatalf("requestedDuration() = %v, %v, want 1h, true", d, ok)
+	}
+
+	csrWithAnnotation := &csrv1.CertificateSigningRequest{}
+	csrWithAnnotation.Annotations = map[string]string{requestDurationAnnotation: "2h"}
+	if d, ok := requestedDuration(csrWithAnnotation); !ok || d != 2*time.Hour {
+		t.Fatalf("requestedDuration() = %v, %v, want 2h, true", d, ok)
+	}
+
+	// The documented annotation format (see requestDurationAnnotation's doc
+	// comment) is a Go duration string; this exercises exactly the example
+	// given there.
+	csrWithDocumentedFormat := &csrv1.CertificateSigningRequest{}
+	csrWithDocumentedFormat.Annotations = map[string]string{requestDurationAnnotation: "1h"}
+	if d, ok := requestedDuration(csrWithDocumentedFormat); !ok || d != time.Hour {
+		t.Fatalf("requestedDuration() = %v, %v, want 1h, true", d, ok)
+	}
+
+	csrWithNeither := &csrv1.CertificateSigningRequest{}
+	if _, ok := requestedDuration(csrWithNeither); ok {
+		t.Fatalf("requestedDuration() reported a duration for a CSR that requested none")
+	}
+}
+
+func TestClampDuration(t *testing.T) {
+	cfg := DurationConfig{MinDuration: time.Hour, MaxDuration: 24 * time.Hour}
+
+	tests := []struct {
+		name      string
+		requested time.Duration
+		want      time.Duration
+		clamped   bool
+	}{
+		{name: "within bounds", requested: 2 * time.Hour, want: 2 * time.Hour},
+		{name: "above max", requested: 48 * time.Hour, want: 24 * time.Hour, clamped: true},
+		{name: "below min", requested: 10 * time.Minute, want: time.Hour, clamped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, msg := clampDuration(tt.requested, cfg)
+			if got != tt.want {
+				t.Errorf("clampDuration() duration = %v, want %v", got, tt.want)
+			}
+			if (msg != "") != tt.clamped {
+				t.Errorf("clampDuration() message = %q, want clamped=%v", msg, tt.clamped)
+			}
+		})
+	}
+}
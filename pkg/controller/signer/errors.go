@@ -0,0 +1,54 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// isRecoverable reports whether err represents a transient condition that is
+// likely to clear on its own - a briefly missing CA secret, an apiserver
+// update conflict, or a network error reaching an external signer - as
+// opposed to a fatal one, such as a malformed CSR, corrupt CA material, or a
+// request outside the allowlist. Recoverable errors are retried via the
+// pending queue (see retry.go) instead of immediately failing the CSR.
+func isRecoverable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, errCANotLoaded) {
+		return true
+	}
+
+	if apierrors.IsNotFound(err) ||
+		apierrors.IsConflict(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTooManyRequests(err) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var extErr *errExternalCARecoverable
+	return errors.As(err, &extErr)
+}
+
+// errExternalCARecoverable wraps a non-2xx response from an external CA
+// endpoint that looks transient - a 5xx or 429 - so it is retried via the
+// pending queue (see retry.go) instead of failing the CSR outright.
+type errExternalCARecoverable struct {
+	status string
+	body   string
+}
+
+func (e *errExternalCARecoverable) Error() string {
+	return fmt.Sprintf("unexpected status %s: %s", e.status, e.body)
+}
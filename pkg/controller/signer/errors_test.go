@@ -0,0 +1,51 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type fakeNetError struct{}
+
+func (fakeNetError) Error() string   { return "fake net error" }
+func (fakeNetError) Timeout() bool   { return true }
+func (fakeNetError) Temporary() bool { return true }
+
+var _ net.Error = fakeNetError{}
+
+func TestIsRecoverable(t *testing.T) {
+	gr := schema.GroupResource{Group: "certificates.k8s.io", Resource: "certificatesigningrequests"}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "errCANotLoaded", err: errCANotLoaded, want: true},
+		{name: "wrapped errCANotLoaded", err: fmt.Errorf("reconcile: %w", errCANotLoaded), want: true},
+		{name: "not found", err: apierrors.NewNotFound(gr, "test"), want: true},
+		{name: "conflict", err: apierrors.NewConflict(gr, "test", errors.New("conflict")), want: true},
+		{name: "service unavailable", err: apierrors.NewServiceUnavailable("unavailable"), want: true},
+		{name: "too many requests", err: apierrors.NewTooManyRequests("retry later", 1), want: true},
+		{name: "net error", err: fakeNetError{}, want: true},
+		{name: "wrapped net error", err: fmt.Errorf("dialing: %w", fakeNetError{}), want: true},
+		{name: "external CA 503", err: &errExternalCARecoverable{status: "503 Service Unavailable"}, want: true},
+		{name: "wrapped external CA 429", err: fmt.Errorf("endpoint-a: %w", &errExternalCARecoverable{status: "429 Too Many Requests"}), want: true},
+		{name: "malformed CSR", err: fmt.Errorf("could not decode certificate request"), want: false},
+		{name: "external CA 400", err: fmt.Errorf("unexpected status 400 Bad Request: nope"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRecoverable(tt.err); got != tt.want {
+				t.Errorf("isRecoverable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package signer
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// defaultHealthProbeInterval is how often configured external CA endpoints
+// are probed for reachability.
+const defaultHealthProbeInterval = 30 * time.Second
+
+// externalCAHealthProber periodically probes every configured external CA
+// endpoint and reports the aggregate result through statusmanager: degraded
+// only once every endpoint in the pool is unreachable, since the signer
+// itself keeps working as long as one endpoint responds.
+type externalCAHealthProber struct {
+	signer   *externalCASigner
+	status   *statusAggregator
+	interval time.Duration
+}
+
+// newExternalCAHealthProber returns a manager.Runnable that probes signer's
+// endpoints until ctx is cancelled.
+func newExternalCAHealthProber(signer *externalCASigner, status *statusAggregator) *externalCAHealthProber {
+	return &externalCAHealthProber{signer: signer, status: status, interval: defaultHealthProbeInterval}
+}
+
+// Start implements manager.Runnable.
+func (p *externalCAHealthProber) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.probeOnce(ctx)
+		}
+	}
+}
+
+func (p *externalCAHealthProber) probeOnce(ctx context.Context) {
+	var lastErr error
+	reachable := 0
+	for _, endpoint := range p.signer.endpoints {
+		probeCtx, cancel := context.WithTimeout(ctx, defaultExternalCATimeout)
+		err := p.signer.Probe(probeCtx, endpoint)
+		cancel()
+		if err != nil {
+			lastErr = err
+			log.Printf("external CA endpoint %s unreachable: %v", endpoint, err)
+			continue
+		}
+		reachable++
+	}
+
+	if reachable == 0 {
+		p.status.setDegraded(statusSourceHealthProbe, "ExternalCAUnreachable",
+			"all configured external CA endpoints are unreachable: "+lastErr.Error())
+		return
+	}
+
+	p.status.setNotDegraded(statusSourceHealthProbe)
+}
@@ -0,0 +1,157 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	csrv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultExternalCATimeout bounds a single round-trip to an external CA
+// endpoint.
+const defaultExternalCATimeout = 10 * time.Second
+
+// ExternalCAConfig configures the BackendExternalCA signer, modeled on
+// swarmkit's ExternalCA: a pool of HTTPS endpoints, tried round-robin on
+// failure, authenticated with a client certificate.
+type ExternalCAConfig struct {
+	// Endpoints is the pool of URLs to POST certificate requests to. Each
+	// endpoint is expected to return a PEM-encoded certificate on success.
+	Endpoints []string
+	// ClientCertSecretNamespace/Name names the secret holding the mTLS
+	// client certificate (tls.crt/tls.key) and CA bundle (ca.crt) used to
+	// authenticate to, and verify, the external endpoints.
+	ClientCertSecretNamespace string
+	ClientCertSecretName      string
+	// Timeout bounds a single request to one endpoint. Defaults to
+	// defaultExternalCATimeout.
+	Timeout time.Duration
+}
+
+// externalCASigner delegates signing to a pool of external HTTPS endpoints,
+// trying each in round-robin order until one succeeds.
+type externalCASigner struct {
+	endpoints []string
+	client    *http.Client
+	next      uint32
+}
+
+func newExternalCASigner(cfg ExternalCAConfig, client crclient.Client) (*externalCASigner, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, fmt.Errorf("external CA backend requires at least one endpoint")
+	}
+
+	secret := &corev1.Secret{}
+	if err := client.Get(context.Background(), types.NamespacedName{
+		Namespace: cfg.ClientCertSecretNamespace,
+		Name:      cfg.ClientCertSecretName,
+	}, secret); err != nil {
+		return nil, fmt.Errorf("could not get external CA client certificate secret: %w", err)
+	}
+
+	clientCert, err := tls.X509KeyPair(secret.Data["tls.crt"], secret.Data["tls.key"])
+	if err != nil {
+		return nil, fmt.Errorf("could not load external CA client certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{clientCert},
+	}
+	if caBundle := secret.Data["ca.crt"]; len(caBundle) != 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("could not parse ca.crt in external CA client certificate secret")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultExternalCATimeout
+	}
+
+	return &externalCASigner{
+		endpoints: cfg.Endpoints,
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: tlsConfig,
+			},
+		},
+	}, nil
+}
+
+// Sign POSTs the certificate request to one of the configured endpoints,
+// trying the rest of the pool in round-robin order if one fails.
+func (s *externalCASigner) Sign(ctx context.Context, csr *csrv1.CertificateSigningRequest, duration time.Duration) ([]byte, error) {
+	start := int(atomic.AddUint32(&s.next, 1)) % len(s.endpoints)
+
+	var lastErr error
+	for i := range s.endpoints {
+		endpoint := s.endpoints[(start+i)%len(s.endpoints)]
+		cert, err := s.signAt(ctx, endpoint, csr.Spec.Request, duration)
+		if err == nil {
+			return cert, nil
+		}
+		lastErr = fmt.Errorf("%s: %w", endpoint, err)
+	}
+
+	return nil, fmt.Errorf("all external CA endpoints failed, last error: %w", lastErr)
+}
+
+func (s *externalCASigner) signAt(ctx context.Context, endpoint string, csrPEM []byte, duration time.Duration) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(csrPEM))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-pem-file")
+	req.Header.Set("X-Requested-Duration", duration.String())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return nil, &errExternalCARecoverable{status: resp.Status, body: string(body)}
+		}
+		return nil, fmt.Errorf("unexpected status %s: %s", resp.Status, body)
+	}
+
+	return body, nil
+}
+
+// Probe checks that an external CA endpoint is reachable over TLS. It is
+// used by the health prober in external_health.go rather than by Sign
+// itself, so a single slow endpoint doesn't block signing.
+func (s *externalCASigner) Probe(ctx context.Context, endpoint string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package signer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	csrv1 "k8s.io/api/certificates/v1"
+)
+
+// newTestEndpoint starts an httptest server that always returns certPEM and
+// records how many requests it received.
+func newTestEndpoint(t *testing.T, certPEM []byte, status int) (*httptest.Server, *int32) {
+	t.Helper()
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(status)
+		w.Write(certPEM)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestExternalCASignerRoundRobin(t *testing.T) {
+	certPEM := []byte("fake-cert")
+	srvA, hitsA := newTestEndpoint(t, certPEM, http.StatusOK)
+	srvB, hitsB := newTestEndpoint(t, certPEM, http.StatusOK)
+
+	s := &externalCASigner{
+		endpoints: []string{srvA.URL, srvB.URL},
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}
+
+	csr := &csrv1.CertificateSigningRequest{Spec: csrv1.CertificateSigningRequestSpec{Request: []byte("fake-csr")}}
+
+	for i := 0; i < 4; i++ {
+		if _, err := s.Sign(context.Background(), csr, time.Hour); err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+	}
+
+	if *hitsA == 0 || *hitsB == 0 {
+		t.Fatalf("expected both endpoints to be used in round-robin, got hitsA=%d hitsB=%d", *hitsA, *hitsB)
+	}
+}
+
+func TestExternalCASignerFailsOverOnError(t *testing.T) {
+	certPEM := []byte("fake-cert")
+	down, hitsDown := newTestEndpoint(t, nil, http.StatusInternalServerError)
+	up, hitsUp := newTestEndpoint(t, certPEM, http.StatusOK)
+
+	// Sign's round-robin start index for a fresh signer always resolves to
+	// index 1 on the first call, so put the failing endpoint there to
+	// deterministically exercise the failover to index 0.
+	s := &externalCASigner{
+		endpoints: []string{up.URL, down.URL},
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}
+
+	csr := &csrv1.CertificateSigningRequest{Spec: csrv1.CertificateSigningRequestSpec{Request: []byte("fake-csr")}}
+
+	cert, err := s.Sign(context.Background(), csr, time.Hour)
+	if err != nil {
+		t.Fatalf("Sign() error = %v, want it to fail over to the healthy endpoint", err)
+	}
+	if string(cert) != string(certPEM) {
+		t.Fatalf("Sign() returned %q, want %q", cert, certPEM)
+	}
+	if *hitsDown == 0 || *hitsUp == 0 {
+		t.Fatalf("expected Sign to try both endpoints, got hitsDown=%d hitsUp=%d", *hitsDown, *hitsUp)
+	}
+}
+
+func TestExternalCASignerAllEndpointsFail(t *testing.T) {
+	down1, _ := newTestEndpoint(t, nil, http.StatusInternalServerError)
+	down2, _ := newTestEndpoint(t, nil, http.StatusBadGateway)
+
+	s := &externalCASigner{
+		endpoints: []string{down1.URL, down2.URL},
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}
+
+	csr := &csrv1.CertificateSigningRequest{Spec: csrv1.CertificateSigningRequestSpec{Request: []byte("fake-csr")}}
+
+	if _, err := s.Sign(context.Background(), csr, time.Hour); err == nil {
+		t.Fatalf("Sign() succeeded, want an error when every endpoint fails")
+	} else if !isRecoverable(err) {
+		t.Errorf("Sign() returned a non-recoverable error for 5xx endpoint failures: %v", err)
+	}
+}
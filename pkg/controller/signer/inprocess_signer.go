@@ -0,0 +1,42 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift/library-go/pkg/crypto"
+	csrv1 "k8s.io/api/certificates/v1"
+)
+
+// caSigner signs in-process using CA material supplied by a caRotator,
+// which keeps it current as the signer-ca secret rotates without requiring
+// a controller restart.
+type caSigner struct {
+	rotator *caRotator
+}
+
+func (s *caSigner) Sign(ctx context.Context, csr *csrv1.CertificateSigningRequest, duration time.Duration) ([]byte, error) {
+	gen, err := s.rotator.selectGeneration(csr)
+	if err != nil {
+		return nil, err
+	}
+
+	certReq, err := decodeCertificateRequest(csr.Spec.Request)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode certificate request: %w", err)
+	}
+
+	// Never issue a certificate that outlives the CA generation signing it,
+	// regardless of what was requested.
+	if remaining := time.Until(gen.cert.NotAfter); duration > remaining {
+		duration = remaining
+	}
+
+	signedCert, err := signCSR(newCertificateTemplate(certReq, duration), certReq.PublicKey, gen.cert, gen.key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign certificate: %w", err)
+	}
+
+	return crypto.EncodeCertificates(signedCert)
+}
@@ -0,0 +1,165 @@
+package signer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	csrv1 "k8s.io/api/certificates/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RetryConfig tunes how recoverable signing errors are retried.
+type RetryConfig struct {
+	// TickerInterval is how often the pending queue re-drives every entry
+	// that is due, independent of any watch event.
+	TickerInterval time.Duration
+	// BaseBackoff is the delay before the first retry; subsequent retries
+	// double it, up to MaxBackoff.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the per-entry backoff.
+	MaxBackoff time.Duration
+	// MaxAttempts is the number of recoverable failures after which the CSR
+	// is finally marked Failed; the MaxAttempts-th failure is not retried.
+	MaxAttempts int
+}
+
+// DefaultRetryConfig matches what swarmkit uses for its pending issuance
+// queue, adjusted for this controller's CA-secret-driven failure modes.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		TickerInterval: 10 * time.Second,
+		BaseBackoff:    10 * time.Second,
+		MaxBackoff:     5 * time.Minute,
+		MaxAttempts:    15,
+	}
+}
+
+var recoverableRetriesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "cno_signer_recoverable_retries_total",
+	Help: "Number of times the signer controller retried a CSR after a recoverable error (e.g. a missing CA secret or an unreachable external signer).",
+})
+
+func init() {
+	metrics.Registry.MustRegister(recoverableRetriesTotal)
+}
+
+// pendingEntry tracks one CSR that failed with a recoverable error.
+type pendingEntry struct {
+	request     reconcile.Request
+	attempts    int
+	nextAttempt time.Time
+}
+
+// pendingTracker holds CSRs that hit a recoverable signing error, re-driving
+// them on an exponential backoff instead of marking them Failed. It never
+// touches the CSR's status itself - callers decide what to do when an entry
+// finally exceeds MaxAttempts.
+type pendingTracker struct {
+	cfg RetryConfig
+
+	mu      sync.Mutex
+	entries map[types.UID]*pendingEntry
+
+	// events carries a GenericEvent for every entry that becomes due, so the
+	// controller can watch it like any other event source.
+	events chan event.GenericEvent
+}
+
+func newPendingTracker(cfg RetryConfig) *pendingTracker {
+	return &pendingTracker{
+		cfg:     cfg,
+		entries: make(map[types.UID]*pendingEntry),
+		events:  make(chan event.GenericEvent, 128),
+	}
+}
+
+// track records a recoverable failure for the CSR identified by uid/request.
+// It returns true once the entry has reached the configured MaxAttempts, at
+// which point it is dropped and the caller should mark the CSR Failed.
+func (t *pendingTracker) track(uid types.UID, request reconcile.Request) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.entries[uid]
+	if !ok {
+		e = &pendingEntry{request: request}
+		t.entries[uid] = e
+	}
+	e.attempts++
+	e.nextAttempt = time.Now().Add(backoff(e.attempts, t.cfg))
+
+	if e.attempts >= t.cfg.MaxAttempts {
+		delete(t.entries, uid)
+		return true
+	}
+
+	recoverableRetriesTotal.Inc()
+	return false
+}
+
+// forget stops tracking a CSR, e.g. because it has since succeeded or been
+// deleted.
+func (t *pendingTracker) forget(uid types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.entries, uid)
+}
+
+// resync enqueues a GenericEvent for every entry whose backoff has elapsed.
+func (t *pendingTracker) resync() {
+	now := time.Now()
+
+	t.mu.Lock()
+	due := make([]reconcile.Request, 0, len(t.entries))
+	for _, e := range t.entries {
+		if now.After(e.nextAttempt) {
+			due = append(due, e.request)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, req := range due {
+		csr := &csrv1.CertificateSigningRequest{}
+		csr.Name = req.Name
+		select {
+		case t.events <- event.GenericEvent{Object: csr}:
+		default:
+			// The channel is full; this entry will be picked up on the next tick.
+		}
+	}
+}
+
+func backoff(attempts int, cfg RetryConfig) time.Duration {
+	d := cfg.BaseBackoff * time.Duration(1<<uint(attempts-1))
+	if d <= 0 || d > cfg.MaxBackoff {
+		return cfg.MaxBackoff
+	}
+	return d
+}
+
+// pendingResyncer is a manager.Runnable that periodically re-drives the
+// pending tracker so recoverable CSRs are retried even without a new watch
+// event.
+type pendingResyncer struct {
+	tracker *pendingTracker
+}
+
+func (p *pendingResyncer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.tracker.cfg.TickerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.tracker.resync()
+		}
+	}
+}
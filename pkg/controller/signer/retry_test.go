@@ -0,0 +1,55 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestBackoff(t *testing.T) {
+	cfg := RetryConfig{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 3, want: 4 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 5, want: 10 * time.Second}, // would be 16s, capped to MaxBackoff
+		{attempts: 30, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempts, cfg); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestPendingTrackerTrack(t *testing.T) {
+	cfg := RetryConfig{BaseBackoff: time.Millisecond, MaxBackoff: time.Second, MaxAttempts: 3}
+	tracker := newPendingTracker(cfg)
+
+	uid := types.UID("test-uid")
+	request := reconcile.Request{}
+
+	if exceeded := tracker.track(uid, request); exceeded {
+		t.Fatalf("track() reported exceeded on the first attempt")
+	}
+	if exceeded := tracker.track(uid, request); exceeded {
+		t.Fatalf("track() reported exceeded on the second attempt")
+	}
+	if exceeded := tracker.track(uid, request); !exceeded {
+		t.Fatalf("track() did not report exceeded after MaxAttempts")
+	}
+
+	// The entry is dropped once it reaches MaxAttempts, so tracking it again
+	// starts over.
+	if exceeded := tracker.track(uid, request); exceeded {
+		t.Fatalf("track() reported exceeded immediately after being dropped")
+	}
+}
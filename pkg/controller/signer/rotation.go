@@ -0,0 +1,395 @@
+package signer
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/openshift/library-go/pkg/crypto"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// errCANotLoaded is returned by the rotator until the rotation controller
+// has observed the signer-ca secret at least once. It is treated as a
+// recoverable error by the signing path (see errors.go), since it clears as
+// soon as the secret is read.
+var errCANotLoaded = errors.New("signer CA material not loaded yet")
+
+// caBundleSecretName holds the union of the previous and current CA
+// certificates, for consumers that need to trust both during the overlap
+// window.
+const caBundleSecretName = "signer-ca-bundle"
+
+// RotationConfig tunes when a CA is considered due for rotation and how
+// long the previous CA is kept available once it has been replaced.
+type RotationConfig struct {
+	// RenewalThreshold flags the CA as due for rotation once its remaining
+	// validity drops below this.
+	RenewalThreshold time.Duration
+	// Overlap is how long the previous CA's key is retained, and advertised
+	// in the CA bundle, after a new CA has taken over.
+	Overlap time.Duration
+	// ForceRotate asks for a fresh CA to be generated immediately, regardless
+	// of RenewalThreshold. It is meant to be set from the ForceRotate
+	// annotation on the operator's config CR and carried in via Config; once
+	// honored it is not repeated for the lifetime of this process.
+	ForceRotate bool
+}
+
+// DefaultRotationConfig gives CA consumers a month's notice and a full day
+// of overlap to pick up a new CA before the old one is dropped.
+func DefaultRotationConfig() RotationConfig {
+	return RotationConfig{
+		RenewalThreshold: 30 * 24 * time.Hour,
+		Overlap:          24 * time.Hour,
+	}
+}
+
+// caGeneration is one version of the signer CA.
+type caGeneration struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// caRotator holds the CA material the in-process signer uses, tracking both
+// the active generation and, during the overlap window, the one it
+// replaced. It is kept up to date by reconcileRotation and consulted by
+// caSigner on every Sign call, so callers never talk to the apiserver
+// directly to get CA material.
+type caRotator struct {
+	cfg RotationConfig
+
+	mu                sync.RWMutex
+	current           *caGeneration
+	previous          *caGeneration
+	previousExpires   time.Time
+	forceRotationDone bool
+}
+
+func newCARotator(cfg RotationConfig) *caRotator {
+	return &caRotator{cfg: cfg}
+}
+
+// bootstrap synchronously loads the current CA material so the signer has
+// something to sign with as soon as the manager starts, without waiting for
+// the rotation controller's first reconcile. A NotFound error is returned
+// as-is; the rotation controller will pick the secret up once it appears.
+func (r *caRotator) bootstrap(ctx context.Context, client crclient.Client, namespace, name string) error {
+	secret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return err
+	}
+
+	cert, err := decodeCertificate(secret.Data["tls.crt"])
+	if err != nil {
+		return fmt.Errorf("unable to decode CA certificate: %w", err)
+	}
+	key, err := decodePrivateKey(secret.Data["tls.key"])
+	if err != nil {
+		return fmt.Errorf("unable to decode CA private key: %w", err)
+	}
+
+	r.observe(cert, key)
+	return nil
+}
+
+// generations returns the current and previous CA generations. The previous
+// one is returned until pruneExpiredPrevious drops it, which rotationPruner
+// does on a one-minute tick after its overlap window has elapsed.
+func (r *caRotator) generations() (current, previous *caGeneration) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current, r.previous
+}
+
+// currentSerial returns the serial number of the active CA generation, so a
+// newly-approved CSR can be pinned against it (see signingCASerialAnnotation)
+// before a rotation that happens while it's in flight could switch it to a
+// CA it wasn't issued against.
+func (r *caRotator) currentSerial() (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.current == nil {
+		return "", false
+	}
+	return serialNumberString(r.current.cert.SerialNumber), true
+}
+
+// shouldForceRotate reports whether the operator CR's ForceRotate annotation
+// was set (via RotationConfig.ForceRotate) and hasn't been carried out yet in
+// this process.
+func (r *caRotator) shouldForceRotate() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cfg.ForceRotate && !r.forceRotationDone
+}
+
+// markForceRotationDone records that a forced rotation has been carried out,
+// so it isn't repeated on every subsequent reconcile.
+func (r *caRotator) markForceRotationDone() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.forceRotationDone = true
+}
+
+// observe updates the rotator with the CA material found in the signer-ca
+// secret. If the certificate's serial number differs from the current
+// generation, the current generation is demoted to previous (with an
+// overlap deadline) and the new material becomes current. It reports a
+// rotation only when an existing generation was replaced; the first
+// observation on an empty rotator is not one.
+func (r *caRotator) observe(cert *x509.Certificate, key crypto.Signer) (rotated bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.current != nil && r.current.cert.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+		// Same CA we already know about; nothing to do.
+		return false
+	}
+
+	rotated = r.current != nil
+	if rotated {
+		r.previous = r.current
+		r.previousExpires = time.Now().Add(r.cfg.Overlap)
+	}
+	r.current = &caGeneration{cert: cert, key: key}
+	return rotated
+}
+
+// pruneExpiredPrevious drops the previous CA generation once its overlap
+// window has elapsed. It returns true if a generation was dropped, so the
+// caller knows to refresh the published bundle.
+func (r *caRotator) pruneExpiredPrevious() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.previous == nil || time.Now().Before(r.previousExpires) {
+		return false
+	}
+	r.previous = nil
+	return true
+}
+
+// serialNumberString mirrors how we key generations for the
+// signing-ca-serial annotation below.
+func serialNumberString(n *big.Int) string {
+	return n.String()
+}
+
+// signingCASerialAnnotation lets a CSR pin which CA generation it expects to
+// be signed by, so a client that fetched the previous CA's public cert just
+// before a rotation can still get a certificate it can verify against that
+// CA during the overlap window.
+const signingCASerialAnnotation = "network.openshift.io/signing-ca-serial"
+
+// selectGeneration picks which CA generation to sign csr with: the pinned
+// one if the CSR names a serial that's still available, otherwise the
+// active one.
+func (r *caRotator) selectGeneration(csr metav1.Object) (*caGeneration, error) {
+	current, previous := r.generations()
+	if current == nil {
+		return nil, errCANotLoaded
+	}
+
+	wanted, ok := csr.GetAnnotations()[signingCASerialAnnotation]
+	if !ok {
+		return current, nil
+	}
+
+	if previous != nil && serialNumberString(previous.cert.SerialNumber) == wanted {
+		return previous, nil
+	}
+	if serialNumberString(current.cert.SerialNumber) == wanted {
+		return current, nil
+	}
+
+	return nil, fmt.Errorf("CSR pins CA serial %s, which is no longer available", wanted)
+}
+
+// bundlePEM renders the current and, if present, previous CA certificates
+// as a single PEM bundle for the signer-ca-bundle secret.
+func (r *caRotator) bundlePEM() ([]byte, error) {
+	current, previous := r.generations()
+	if current == nil {
+		return nil, errCANotLoaded
+	}
+
+	certs := []*x509.Certificate{current.cert}
+	if previous != nil {
+		certs = append(certs, previous.cert)
+	}
+	return crypto.EncodeCertificates(certs...)
+}
+
+// reconcileRotation watches the signer-ca secret, feeds new CA material into
+// the shared caRotator, republishes signer-ca-bundle, and prunes the
+// previous generation once its overlap window has elapsed.
+type reconcileRotation struct {
+	client    crclient.Client
+	status    *statusAggregator
+	rotator   *caRotator
+	namespace string
+	name      string
+}
+
+func addRotationController(mgr manager.Manager, status *statusAggregator, rotator *caRotator, namespace, name string) error {
+	r := &reconcileRotation{
+		client:    mgr.GetClient(),
+		status:    status,
+		rotator:   rotator,
+		namespace: namespace,
+		name:      name,
+	}
+
+	c, err := controller.New("signer-ca-rotation-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	return mgr.Add(&rotationPruner{rotator: rotator, reconcile: r})
+}
+
+func (r *reconcileRotation) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	if request.Namespace != r.namespace || request.Name != r.name {
+		return reconcile.Result{}, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: r.namespace, Name: r.name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	cert, err := decodeCertificate(secret.Data["tls.crt"])
+	if err != nil {
+		log.Printf("signer-ca-rotation-controller: unable to decode %s/%s tls.crt: %v", r.namespace, r.name, err)
+		return reconcile.Result{}, nil
+	}
+	key, err := decodePrivateKey(secret.Data["tls.key"])
+	if err != nil {
+		log.Printf("signer-ca-rotation-controller: unable to decode %s/%s tls.key: %v", r.namespace, r.name, err)
+		return reconcile.Result{}, nil
+	}
+
+	if rotated := r.rotator.observe(cert, key); rotated {
+		log.Printf("signer-ca-rotation-controller: observed new CA generation (serial %s, expires %s)", cert.SerialNumber, cert.NotAfter)
+	}
+
+	if r.rotator.shouldForceRotate() {
+		if err := r.forceRotate(ctx, secret, cert); err != nil {
+			log.Printf("signer-ca-rotation-controller: force-rotate requested but failed: %v", err)
+			r.status.setDegraded(statusSourceRotation, "ForceRotateFailed", err.Error())
+			return reconcile.Result{}, nil
+		}
+		// secret.Update above will trigger another reconcile that observes
+		// the new CA material and republishes the bundle under it.
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.republishBundle(ctx); err != nil {
+		r.status.setDegraded(statusSourceRotation, "CABundlePublishFailure", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	if remaining := time.Until(cert.NotAfter); remaining < r.rotator.cfg.RenewalThreshold {
+		r.status.setDegraded(statusSourceRotation, "RotationDue",
+			fmt.Sprintf("signer CA %s/%s expires at %s and is due for rotation; awaiting a new CA from the PKI controller", r.namespace, r.name, cert.NotAfter))
+	} else {
+		r.status.setNotDegraded(statusSourceRotation)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// forceRotate generates a fresh self-signed CA and writes it into the
+// signer-ca secret, honoring the operator CR's ForceRotate request. It marks
+// the request as handled so a subsequent reconcile - triggered by this very
+// update - doesn't generate another one.
+func (r *reconcileRotation) forceRotate(ctx context.Context, secret *corev1.Secret, current *x509.Certificate) error {
+	certPEM, keyPEM, err := generateSelfSignedCA(current.Subject.CommonName, r.rotator.cfg.RenewalThreshold*2)
+	if err != nil {
+		return fmt.Errorf("generating new CA: %w", err)
+	}
+
+	secret.Data["tls.crt"] = certPEM
+	secret.Data["tls.key"] = keyPEM
+	if err := r.client.Update(ctx, secret); err != nil {
+		return fmt.Errorf("updating %s/%s with the new CA: %w", r.namespace, r.name, err)
+	}
+
+	r.rotator.markForceRotationDone()
+	log.Printf("signer-ca-rotation-controller: force-rotate requested, generated a fresh CA for %s/%s", r.namespace, r.name)
+	return nil
+}
+
+func (r *reconcileRotation) republishBundle(ctx context.Context) error {
+	bundle, err := r.rotator.bundlePEM()
+	if err != nil {
+		return fmt.Errorf("unable to render CA bundle: %w", err)
+	}
+
+	secret := &corev1.Secret{}
+	err = r.client.Get(ctx, types.NamespacedName{Namespace: r.namespace, Name: caBundleSecretName}, secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: r.namespace, Name: caBundleSecretName},
+			Data:       map[string][]byte{"ca-bundle.crt": bundle},
+		}
+		return r.client.Create(ctx, secret)
+	case err != nil:
+		return fmt.Errorf("unable to get %s/%s: %w", r.namespace, caBundleSecretName, err)
+	}
+
+	if string(secret.Data["ca-bundle.crt"]) == string(bundle) {
+		return nil
+	}
+	secret.Data = map[string][]byte{"ca-bundle.crt": bundle}
+	return r.client.Update(ctx, secret)
+}
+
+// rotationPruner periodically drops the previous CA generation once its
+// overlap window has elapsed, and republishes the bundle to match.
+type rotationPruner struct {
+	rotator   *caRotator
+	reconcile *reconcileRotation
+}
+
+func (p *rotationPruner) Start(ctx context.Context) error {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if p.rotator.pruneExpiredPrevious() {
+				log.Println("signer-ca-rotation-controller: previous CA generation's overlap window elapsed, dropping it")
+				if err := p.reconcile.republishBundle(ctx); err != nil {
+					log.Printf("signer-ca-rotation-controller: unable to republish CA bundle after pruning: %v", err)
+				}
+			}
+		}
+	}
+}
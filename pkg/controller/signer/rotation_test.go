@@ -0,0 +1,112 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// selfSignedTestCert builds a throwaway self-signed CA certificate with the
+// given serial number, for exercising caRotator without a real signer-ca
+// secret.
+func selfSignedTestCert(t *testing.T, serial int64, notAfter time.Time) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parsing certificate: %v", err)
+	}
+	return cert, key
+}
+
+func TestCARotatorObserve(t *testing.T) {
+	r := newCARotator(RotationConfig{Overlap: time.Hour})
+
+	certA, keyA := selfSignedTestCert(t, 1, time.Now().Add(24*time.Hour))
+	if rotated := r.observe(certA, keyA); rotated {
+		t.Fatalf("observe() on an empty rotator reported a rotation")
+	}
+
+	current, previous := r.generations()
+	if current == nil || current.cert.SerialNumber.Int64() != 1 || previous != nil {
+		t.Fatalf("unexpected generations after first observe: current=%v previous=%v", current, previous)
+	}
+
+	certB, keyB := selfSignedTestCert(t, 2, time.Now().Add(24*time.Hour))
+	if rotated := r.observe(certB, keyB); !rotated {
+		t.Fatalf("observe() with a new serial did not report a rotation")
+	}
+
+	current, previous = r.generations()
+	if current == nil || current.cert.SerialNumber.Int64() != 2 {
+		t.Fatalf("expected current generation to be serial 2, got %v", current)
+	}
+	if previous == nil || previous.cert.SerialNumber.Int64() != 1 {
+		t.Fatalf("expected previous generation to be serial 1, got %v", previous)
+	}
+
+	if rotated := r.observe(certB, keyB); rotated {
+		t.Fatalf("re-observing the same certificate reported a rotation")
+	}
+}
+
+func TestCARotatorSelectGeneration(t *testing.T) {
+	r := newCARotator(RotationConfig{Overlap: time.Hour})
+
+	certA, keyA := selfSignedTestCert(t, 1, time.Now().Add(24*time.Hour))
+	r.observe(certA, keyA)
+	certB, keyB := selfSignedTestCert(t, 2, time.Now().Add(24*time.Hour))
+	r.observe(certB, keyB)
+
+	unpinned := &metav1.ObjectMeta{}
+	gen, err := r.selectGeneration(unpinned)
+	if err != nil {
+		t.Fatalf("selectGeneration() error = %v", err)
+	}
+	if gen.cert.SerialNumber.Int64() != 2 {
+		t.Fatalf("expected the current generation (2) for an unpinned CSR, got %v", gen.cert.SerialNumber)
+	}
+
+	pinnedToPrevious := &metav1.ObjectMeta{
+		Annotations: map[string]string{signingCASerialAnnotation: serialNumberString(certA.SerialNumber)},
+	}
+	gen, err = r.selectGeneration(pinnedToPrevious)
+	if err != nil {
+		t.Fatalf("selectGeneration() error = %v", err)
+	}
+	if gen.cert.SerialNumber.Int64() != 1 {
+		t.Fatalf("expected the pinned previous generation (1), got %v", gen.cert.SerialNumber)
+	}
+
+	staleSerial := &metav1.ObjectMeta{
+		Annotations: map[string]string{signingCASerialAnnotation: "999"},
+	}
+	if _, err := r.selectGeneration(staleSerial); err == nil {
+		t.Fatalf("expected an error for a CSR pinned to a serial no longer available")
+	}
+}
@@ -7,14 +7,13 @@ import (
 
 	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
 	"github.com/openshift/cluster-network-operator/pkg/controller/statusmanager"
-	"github.com/openshift/library-go/pkg/crypto"
 	csrv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
 
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,27 +27,88 @@ import (
 
 const signerName = "network.openshift.io/signer"
 
-// Add controller and start it when the Manager is started.
-func Add(mgr manager.Manager, status *statusmanager.StatusManager, _ *cnoclient.Client) error {
-	reconciler, err := newReconciler(mgr, status)
+// Add controller and start it when the Manager is started. cfg carries the
+// cluster-admin-facing knobs for this signer, translated from the operator's
+// config CR by the caller; its zero value runs with this package's defaults.
+func Add(mgr manager.Manager, status *statusmanager.StatusManager, _ *cnoclient.Client, cfg Config) error {
+	backend := cfg.backend()
+
+	// Several independent goroutines - this reconciler, the CA rotation
+	// controller, and the external CA health prober - all report against the
+	// same statusmanager.CertificateSigner key. Route them all through one
+	// aggregator so none of them can clobber another's degraded condition.
+	agg := newStatusAggregator(status)
+
+	reconciler, err := newReconciler(mgr, agg, backend, cfg)
 	if err != nil {
 		return err
 	}
-	return add(mgr, reconciler)
+
+	r := reconciler.(*ReconcileCSR)
+
+	if external, ok := r.signer.(*externalCASigner); ok {
+		if err := mgr.Add(newExternalCAHealthProber(external, agg)); err != nil {
+			return err
+		}
+	}
+
+	if r.rotator != nil {
+		if err := addRotationController(mgr, agg, r.rotator, backend.InProcess.SecretNamespace, backend.InProcess.SecretName); err != nil {
+			return err
+		}
+	}
+
+	if err := mgr.Add(&pendingResyncer{tracker: r.pending}); err != nil {
+		return err
+	}
+
+	if err := addCleanerController(mgr, cfg.cleaner()); err != nil {
+		return err
+	}
+
+	return add(mgr, r)
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager, status *statusmanager.StatusManager) (reconcile.Reconciler, error) {
+func newReconciler(mgr manager.Manager, status *statusAggregator, backend BackendConfig, cfg Config) (reconcile.Reconciler, error) {
 	// We need a clientset in order to UpdateApproval() of the CertificateSigningRequest
 	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
 	if err != nil {
 		return nil, err
 	}
-	return &ReconcileCSR{client: mgr.GetClient(), scheme: mgr.GetScheme(), status: status, clientset: clientset}, nil
+
+	var rotator *caRotator
+	if backend.Kind == "" || backend.Kind == BackendInProcess {
+		rotator = newCARotator(cfg.rotation())
+		if err := rotator.bootstrap(context.TODO(), mgr.GetClient(), backend.InProcess.SecretNamespace, backend.InProcess.SecretName); err != nil {
+			log.Printf("signer-controller: CA not yet available, will pick it up once the rotation controller observes it: %v", err)
+		}
+	}
+
+	signer, err := newSigner(backend, mgr.GetClient(), rotator)
+	if err != nil {
+		return nil, err
+	}
+
+	retryConfig := DefaultRetryConfig()
+
+	return &ReconcileCSR{
+		client:    mgr.GetClient(),
+		scheme:    mgr.GetScheme(),
+		status:    status,
+		clientset: clientset,
+		recorder:  mgr.GetEventRecorderFor("signer-controller"),
+		allowlist: cfg.allowlist(),
+		duration:  cfg.duration(),
+		signer:    signer,
+		rotator:   rotator,
+		retry:     retryConfig,
+		pending:   newPendingTracker(retryConfig),
+	}, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r *ReconcileCSR) error {
 	// Create a new controller
 	c, err := controller.New("signer-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -61,6 +121,13 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch the pending tracker's channel so recoverable errors get
+	// re-reconciled on backoff, without waiting for another CSR watch event.
+	err = c.Watch(&source.Channel{Source: r.pending.events}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -68,10 +135,10 @@ var _ reconcile.Reconciler = &ReconcileCSR{}
 
 // ReconcileCSR reconciles a cluster CertificateSigningRequest object. This
 // will watch for changes to CertificateSigningRequest resources with
-// SignerName == signerName. It will automatically approve these requests for
-// signing. This assumes that the cluster has been configured in a way that
-// no bad actors can make certificate signing requests. In future, we may decide
-// to implement a scheme that would use a one-time token to validate a request.
+// SignerName == signerName. Before approving a request it verifies, via a
+// SubjectAccessReview, that the submitter actually holds approve permission
+// for this signer, and that the request content (Common Name, SANs, key
+// usages, organizations) falls within the configured allowlist.
 //
 // All requests will be signed using a CA, that is currently generated by
 // the OperatorPKI, and the signed certificate will be returned in the status.
@@ -83,7 +150,36 @@ type ReconcileCSR struct {
 	// that reads objects from the cache and writes to the apiserver
 	client crclient.Client
 	scheme *runtime.Scheme
-	status *statusmanager.StatusManager
+	status *statusAggregator
+
+	// recorder emits one event per approve/deny decision for auditability.
+	recorder record.EventRecorder
+
+	// allowlist constrains the Common Names, SANs, organizations and key
+	// usages this signer will approve. Surfaced on the operator config so
+	// cluster admins can tighten the defaults.
+	allowlist AllowlistConfig
+
+	// duration bounds the lifetime signed certificates may be given.
+	// Surfaced on the operator config so cluster admins can tune it.
+	duration DurationConfig
+
+	// signer turns approved CSRs into signed certificates, either
+	// in-process or by delegating to an external CA. Selectable via the
+	// operator config.
+	signer Signer
+
+	// rotator holds CA material for the in-process backend and is kept
+	// current by the signer-ca-rotation-controller. Nil when signer is an
+	// external CA backend.
+	rotator *caRotator
+
+	// retry tunes how recoverable signing errors are retried before a CSR
+	// is finally marked Failed.
+	retry RetryConfig
+	// pending tracks CSRs that hit a recoverable signing error so they can
+	// be re-driven instead of immediately failed.
+	pending *pendingTracker
 
 	// Note: We need a Clientset as the controller-runtime client does not
 	// support non-CRUD subresources (see
@@ -123,16 +219,56 @@ func (r *ReconcileCSR) Reconcile(ctx context.Context, request reconcile.Request)
 		return reconcile.Result{}, nil
 	}
 
-	// We will make the assumption that anyone with permission to issue a
-	// certificate signing request to this signer is automatically approved. This
-	// is somewhat protected by permissions on the CSR resource.
-	// TODO: We may need a more robust way to do this later
 	if !isCertificateRequestApproved(csr) {
+		// Parse the request up front so we can both authorize and validate
+		// its content before it is ever approved.
+		certReq, err := decodeCertificateRequest(csr.Spec.Request)
+		if err != nil {
+			updateCSRStatusConditions(r, csr, "CSRDecodeFailure",
+				fmt.Sprintf("Could not decode Certificate Request: %v", err))
+			return reconcile.Result{}, nil
+		}
+
+		allowed, err := r.isApprovalAuthorized(ctx, csr)
+		if err != nil {
+			log.Printf("Unable to run SubjectAccessReview for %v and signer %v: %v", request.Name, signerName, err)
+			return reconcile.Result{}, err
+		}
+		if !allowed {
+			return r.denyCSR(ctx, csr, "NotAuthorized",
+				fmt.Sprintf("user %q is not permitted to request approval of certificates from %s", csr.Spec.Username, signerName))
+		}
+
+		if err := validateCSRContent(csr, certReq, r.allowlist); err != nil {
+			return r.denyCSR(ctx, csr, "DisallowedRequest", err.Error())
+		}
+
+		// Pin this CSR to the CA generation that's active right now, so that
+		// if a rotation happens while it's in flight it still gets signed
+		// against the CA its submitter expects, for as long as that
+		// generation remains in its overlap window (see selectGeneration).
+		// This has to go through a real metadata update: the approval call
+		// below is a PUT to the approval subresource, and subresource updates
+		// reset ObjectMeta - including annotations - to whatever is already
+		// in etcd, so riding along on that request would silently drop it.
+		if r.rotator != nil {
+			if serial, ok := r.rotator.currentSerial(); ok {
+				if csr.Annotations == nil {
+					csr.Annotations = map[string]string{}
+				}
+				csr.Annotations[signingCASerialAnnotation] = serial
+				if err := r.client.Update(ctx, csr); err != nil {
+					log.Printf("Unable to pin CA generation for %v: %v", request.Name, err)
+					return reconcile.Result{}, err
+				}
+			}
+		}
+
 		csr.Status.Conditions = append(csr.Status.Conditions, csrv1.CertificateSigningRequestCondition{
 			Type:    csrv1.CertificateApproved,
 			Status:  "True",
-			Reason:  "AutoApproved",
-			Message: "Automatically approved by " + signerName})
+			Reason:  "Approved",
+			Message: "Approved by " + signerName})
 		// Update status to "Approved"
 		//nolint:staticcheck
 		csr, err = r.clientset.CertificatesV1().CertificateSigningRequests().UpdateApproval(ctx, request.Name, csr, metav1.UpdateOptions{})
@@ -140,6 +276,7 @@ func (r *ReconcileCSR) Reconcile(ctx context.Context, request reconcile.Request)
 			log.Printf("Unable to approve certificate for %v and signer %v: %v", request.Name, signerName, err)
 			return reconcile.Result{}, err
 		}
+		r.recorder.Eventf(csr, corev1.EventTypeNormal, "Approved", "Approved by %s", signerName)
 
 		// As the update from UpdateApproval() will get reconciled, we
 		// no longer need to deal with this request
@@ -148,66 +285,65 @@ func (r *ReconcileCSR) Reconcile(ctx context.Context, request reconcile.Request)
 
 	// From this, point we are dealing with an approved CSR
 
-	// Get our CA that was created by the operatorpki.
-	caSecret := &corev1.Secret{}
-	err = r.client.Get(ctx, types.NamespacedName{Namespace: "openshift-ovn-kubernetes", Name: "signer-ca"}, caSecret)
-	if err != nil {
-		signerFailure(r, csr, "CAFailure",
-			fmt.Sprintf("Could not get CA certificate and key: %v", err))
-		return reconcile.Result{}, err
+	// Work out the lifetime to sign for: honor what the CSR asked for via
+	// ExpirationSeconds or the cert-manager request-duration annotation,
+	// falling back to the configured maximum, then clamp to [min, max].
+	requested, ok := requestedDuration(csr)
+	if !ok {
+		requested = r.duration.MaxDuration
 	}
-
-	// Decode the certificate request from PEM format.
-	certReq, err := decodeCertificateRequest(csr.Spec.Request)
-	if err != nil {
-		// We dont degrade the status of the controller as this is due to a
-		// malformed CSR rather than an issue with the controller.
-		updateCSRStatusConditions(r, csr, "CSRDecodeFailure",
-			fmt.Sprintf("Could not decode Certificate Request: %v", err))
-		return reconcile.Result{}, nil
+	duration, clampMessage := clampDuration(requested, r.duration)
+	if clampMessage != "" {
+		csr.Status.Conditions = append(csr.Status.Conditions, csrv1.CertificateSigningRequestCondition{
+			Type:    csrv1.CertificateApproved,
+			Status:  "True",
+			Reason:  "DurationClamped",
+			Message: clampMessage})
+		r.recorder.Eventf(csr, corev1.EventTypeNormal, "DurationClamped", clampMessage)
 	}
 
-	// Decode the CA certificate from PEM format.
-	caCert, err := decodeCertificate(caSecret.Data["tls.crt"])
+	// Sign the certificate, either in-process or via an external CA,
+	// depending on how the signer backend is configured.
+	signedCert, err := r.signer.Sign(ctx, csr, duration)
 	if err != nil {
-		signerFailure(r, csr, "CorruptCACert",
-			fmt.Sprintf("Unable to decode CA certificate for %v: %v", signerName, err))
-		return reconcile.Result{}, nil
+		return r.handleRecoverableError(csr, request, "SigningFailure",
+			fmt.Sprintf("Unable to sign certificate for %v and signer %v: %v", request.Name, signerName, err), err)
 	}
 
-	// Decode the CA key from PEM format.
-	caKey, err := decodePrivateKey(caSecret.Data["tls.key"])
-	if err != nil {
-		signerFailure(r, csr, "CorruptCAKey",
-			fmt.Sprintf("Unable to decode CA private key for %v: %v", signerName, err))
-		return reconcile.Result{}, nil
-	}
+	csr.Status.Certificate = signedCert
 
-	// Create a new certificate using the certificate template and certificate.
-	// We can then sign this using the CA.
-	signedCert, err := signCSR(newCertificateTemplate(certReq), certReq.PublicKey, caCert, caKey)
+	err = r.client.Status().Update(ctx, csr)
 	if err != nil {
-		signerFailure(r, csr, "SigningFailure",
-			fmt.Sprintf("Unable to sign certificate for %v and signer %v: %v", request.Name, signerName, err))
-		return reconcile.Result{}, nil
+		return r.handleRecoverableError(csr, request, "StatusUpdateFailure",
+			fmt.Sprintf("Unable to update signed certificate for %v and signer %v: %v", request.Name, signerName, err), err)
 	}
 
-	// Encode the certificate into PEM format and add to the status of the CSR
-	csr.Status.Certificate, err = crypto.EncodeCertificates(signedCert)
-	if err != nil {
-		signerFailure(r, csr, "EncodeFailure",
-			fmt.Sprintf("Could not encode certificate: %v", err))
+	r.pending.forget(csr.UID)
+	log.Printf("Certificate signed, issued and approved for %s by %s", request.Name, signerName)
+	r.status.setNotDegraded(statusSourceReconciler)
+	return reconcile.Result{}, nil
+}
+
+// handleRecoverableError decides what to do with an error encountered while
+// signing or persisting a certificate. Fatal errors keep this controller's
+// long-standing behavior of writing a CertificateFailed condition.
+// Recoverable ones (a briefly missing CA secret, an apiserver conflict, an
+// unreachable external signer) are tracked in the pending queue and retried
+// instead, until MaxAttempts is exceeded.
+func (r *ReconcileCSR) handleRecoverableError(csr *csrv1.CertificateSigningRequest, request reconcile.Request, reason, message string, cause error) (reconcile.Result, error) {
+	if !isRecoverable(cause) {
+		signerFailure(r, csr, reason, message)
+		r.pending.forget(csr.UID)
 		return reconcile.Result{}, nil
 	}
 
-	err = r.client.Status().Update(ctx, csr)
-	if err != nil {
-		log.Printf("Unable to update signed certificate for %v and signer %v: %v", request.Name, signerName, err)
-		return reconcile.Result{}, err
+	if giveUp := r.pending.track(csr.UID, request); giveUp {
+		signerFailure(r, csr, reason, fmt.Sprintf("%s (giving up after %d recoverable failures)", message, r.retry.MaxAttempts))
+		return reconcile.Result{}, nil
 	}
 
-	log.Printf("Certificate signed, issued and approved for %s by %s", request.Name, signerName)
-	r.status.SetNotDegraded(statusmanager.CertificateSigner)
+	log.Printf("%s: %s (will retry)", reason, message)
+	r.status.setDegraded(statusSourceReconciler, reason, message)
 	return reconcile.Result{}, nil
 }
 
@@ -235,21 +371,47 @@ func getCertApprovalCondition(status *csrv1.CertificateSigningRequestStatus) (ap
 func signerFailure(r *ReconcileCSR, csr *csrv1.CertificateSigningRequest, reason string, message string) {
 	log.Printf("%s: %s", reason, message)
 	updateCSRStatusConditions(r, csr, reason, message)
-	r.status.SetDegraded(statusmanager.CertificateSigner, reason, message)
+	r.status.setDegraded(statusSourceReconciler, reason, message)
+}
+
+// denyCSR marks a CSR as denied, records an event explaining why, and
+// returns the values Reconcile should return to the controller-runtime.
+func (r *ReconcileCSR) denyCSR(ctx context.Context, csr *csrv1.CertificateSigningRequest, reason, message string) (reconcile.Result, error) {
+	log.Printf("Denying CSR %s for signer %s: %s: %s", csr.Name, signerName, reason, message)
+
+	csr.Status.Conditions = append(csr.Status.Conditions, csrv1.CertificateSigningRequestCondition{
+		Type:               csrv1.CertificateDenied,
+		Status:             "True",
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+		LastUpdateTime:     metav1.Now()})
+
+	//nolint:staticcheck
+	updated, err := r.clientset.CertificatesV1().CertificateSigningRequests().UpdateApproval(ctx, csr.Name, csr, metav1.UpdateOptions{})
+	if err != nil {
+		log.Printf("Unable to update denial for %v and signer %v: %v", csr.Name, signerName, err)
+		return reconcile.Result{}, err
+	}
+
+	r.recorder.Eventf(updated, corev1.EventTypeWarning, reason, message)
+	return reconcile.Result{}, nil
 }
 
 // Update the status conditions on the CSR object
 func updateCSRStatusConditions(r *ReconcileCSR, csr *csrv1.CertificateSigningRequest, reason string, message string) {
 	csr.Status.Conditions = append(csr.Status.Conditions, csrv1.CertificateSigningRequestCondition{
-		Type:    csrv1.CertificateFailed,
-		Status:  "True",
-		Reason:  reason,
-		Message: message})
+		Type:               csrv1.CertificateFailed,
+		Status:             "True",
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+		LastUpdateTime:     metav1.Now()})
 
 	err := r.client.Status().Update(context.TODO(), csr)
 	if err != nil {
 		log.Printf("Could not update CSR status: %v", err)
-		r.status.SetDegraded(statusmanager.CertificateSigner, "UpdateFailure",
+		r.status.setDegraded(statusSourceReconciler, "UpdateFailure",
 			fmt.Sprintf("Unable to update csr: %v", err))
 	}
 }
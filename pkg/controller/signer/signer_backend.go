@@ -0,0 +1,78 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	csrv1 "k8s.io/api/certificates/v1"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BackendKind selects which Signer implementation the controller uses to
+// turn approved CSRs into certificates.
+type BackendKind string
+
+const (
+	// BackendInProcess signs using the signer-ca secret produced by the
+	// OperatorPKI, in the controller's own process. This is the default
+	// and preserves today's behavior.
+	BackendInProcess BackendKind = "InProcess"
+	// BackendExternalCA delegates signing to one or more external HTTPS
+	// endpoints, authenticating with a client certificate.
+	BackendExternalCA BackendKind = "ExternalCA"
+)
+
+// BackendConfig selects and configures the signing backend.
+type BackendConfig struct {
+	Kind BackendKind
+
+	// InProcess configures the BackendInProcess backend.
+	InProcess InProcessConfig
+
+	// ExternalCA configures the BackendExternalCA backend.
+	ExternalCA ExternalCAConfig
+}
+
+// InProcessConfig names the secret holding the CA certificate and key used
+// to sign in-process.
+type InProcessConfig struct {
+	SecretNamespace string
+	SecretName      string
+}
+
+// DefaultBackendConfig signs in-process against the signer-ca secret, as
+// the controller has always done.
+func DefaultBackendConfig() BackendConfig {
+	return BackendConfig{
+		Kind: BackendInProcess,
+		InProcess: InProcessConfig{
+			SecretNamespace: "openshift-ovn-kubernetes",
+			SecretName:      "signer-ca",
+		},
+	}
+}
+
+// Signer turns an approved certificate signing request into a signed,
+// PEM-encoded certificate. Implementations may sign in-process against a
+// local CA or delegate to an external CA service.
+type Signer interface {
+	// Sign issues a certificate for csr, valid for the given duration, and
+	// returns it PEM-encoded. The full CSR object, rather than just its PEM
+	// request, is passed so implementations that pin a specific CA
+	// generation (see signingCASerialAnnotation) can read its annotations.
+	Sign(ctx context.Context, csr *csrv1.CertificateSigningRequest, duration time.Duration) ([]byte, error)
+}
+
+// newSigner builds the Signer selected by cfg. rotator supplies CA material
+// to the in-process backend; it is ignored by the external CA backend.
+func newSigner(cfg BackendConfig, client crclient.Client, rotator *caRotator) (Signer, error) {
+	switch cfg.Kind {
+	case "", BackendInProcess:
+		return &caSigner{rotator: rotator}, nil
+	case BackendExternalCA:
+		return newExternalCASigner(cfg.ExternalCA, client)
+	default:
+		return nil, fmt.Errorf("unknown signer backend %q", cfg.Kind)
+	}
+}
@@ -0,0 +1,87 @@
+package signer
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openshift/cluster-network-operator/pkg/controller/statusmanager"
+)
+
+// Source names for statusAggregator, one per goroutine that independently
+// reports on the signer's health.
+const (
+	statusSourceReconciler  = "signer-controller"
+	statusSourceRotation    = "signer-ca-rotation-controller"
+	statusSourceHealthProbe = "external-ca-health-prober"
+)
+
+// statusAggregator coordinates status.SetDegraded/SetNotDegraded calls made
+// by several independent goroutines - the CSR reconciler, the CA rotation
+// controller, and the external CA health prober - that would otherwise all
+// report against the single statusmanager.CertificateSigner key directly.
+// Without it, whichever one last ticked could call SetNotDegraded and
+// clobber a real degraded condition another had set moments earlier (e.g.
+// the health prober clearing a signing failure, or the rotation controller's
+// "not due" branch clearing an active ExternalCAUnreachable). Each source
+// reports its own health here; the published status only clears once every
+// source is healthy, and otherwise surfaces every degraded source together.
+type statusAggregator struct {
+	status *statusmanager.StatusManager
+
+	mu       sync.Mutex
+	degraded map[string]degradedReport
+}
+
+type degradedReport struct {
+	reason  string
+	message string
+}
+
+func newStatusAggregator(status *statusmanager.StatusManager) *statusAggregator {
+	return &statusAggregator{status: status, degraded: map[string]degradedReport{}}
+}
+
+// setDegraded records that source is degraded for the given reason/message
+// and republishes the combined status.
+func (a *statusAggregator) setDegraded(source, reason, message string) {
+	a.mu.Lock()
+	a.degraded[source] = degradedReport{reason: reason, message: message}
+	a.mu.Unlock()
+	a.publish()
+}
+
+// setNotDegraded records that source is healthy and republishes the combined
+// status, which stays degraded if any other source is still unhealthy.
+func (a *statusAggregator) setNotDegraded(source string) {
+	a.mu.Lock()
+	delete(a.degraded, source)
+	a.mu.Unlock()
+	a.publish()
+}
+
+func (a *statusAggregator) publish() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.degraded) == 0 {
+		a.status.SetNotDegraded(statusmanager.CertificateSigner)
+		return
+	}
+
+	sources := make([]string, 0, len(a.degraded))
+	for s := range a.degraded {
+		sources = append(sources, s)
+	}
+	sort.Strings(sources)
+
+	reasons := make([]string, 0, len(sources))
+	messages := make([]string, 0, len(sources))
+	for _, s := range sources {
+		r := a.degraded[s]
+		reasons = append(reasons, r.reason)
+		messages = append(messages, s+": "+r.message)
+	}
+
+	a.status.SetDegraded(statusmanager.CertificateSigner, strings.Join(reasons, ","), strings.Join(messages, "; "))
+}